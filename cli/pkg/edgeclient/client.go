@@ -18,38 +18,90 @@ package edgeclient
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/kubeflow/model-registry/pkg/openapi"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
 	tektonclientset "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/opendatahub-io/ai-edge/cli/pkg/edgeclient/features"
+	"github.com/opendatahub-io/ai-edge/cli/pkg/edgeclient/reporter"
 	"github.com/opendatahub-io/ai-edge/cli/pkg/modelregistry"
 )
 
+// PipelineSource describes how to resolve the aiedge-e2e pipeline definition used to build a model container image.
+//
+// When ResolverType is empty, the pipeline is assumed to be a PipelineRef by name in the target namespace (the
+// existing behavior). When ResolverType is set, it is used as the Tekton resolver name (e.g. "git", "bundles",
+// "hub", "cluster") and Params is passed through as the resolver's parameters, allowing the pipeline definition to
+// be pinned to a specific git revision or OCI bundle for reproducible edge builds.
+type PipelineSource struct {
+	ResolverType string
+	Params       map[string]string
+}
+
 // Client is a client representing the edge environment
 //
 // This client can be used to create and manage models and model container images suitable for deployment in edge environments.
 type Client struct {
 	modelRegistryClient *modelregistry.Client
+
+	// trustPolicies, when set, are the fixed VerificationPolicy objects used to verify the resolved aiedge-e2e
+	// pipeline before a PipelineRun is created. trustPolicyNamespace is used instead when trustPolicies is empty:
+	// the policies are loaded from that namespace on every build. See SetTrustPolicy.
+	trustPolicies        []tektonv1alpha1.VerificationPolicy
+	trustPolicyNamespace string
+
+	// reporter, when running, mirrors the state of in-progress and completed aiedge-e2e PipelineRuns back into
+	// the model registry. See StartReporter.
+	reporter *reporter.Reporter
+
+	// features gates which of the above experimental capabilities are active. See NewClientWithFeatures.
+	features features.FeatureFlags
 }
 
 // NewClient creates a new Client to interact with the edge environment. It requires the URL of the model registry service.
 //
 // This client can be used to create and manage models and model container images suitable for deployment in edge environments.
+// Experimental capabilities (Tekton Resolvers, pipeline verification, OCI bundle references, provenance recording)
+// are gated behind features.DefaultFeatureFlags(); use NewClientWithFeatures to override them.
 func NewClient(modelRegistryURL string) *Client {
 	if !strings.Contains(modelRegistryURL, "://") {
 		modelRegistryURL = "http://" + modelRegistryURL
 	}
 	return &Client{
 		modelRegistryClient: modelregistry.NewClient(modelRegistryURL),
+		features:            features.DefaultFeatureFlags(),
+	}
+}
+
+// NewClientWithFeatures creates a new Client like NewClient, but with explicit feature flags instead of the
+// defaults, so callers can opt into experimental behavior without waiting for a cluster-wide
+// ai-edge-feature-flags ConfigMap to take effect.
+func NewClientWithFeatures(modelRegistryURL string, ff features.FeatureFlags) *Client {
+	c := NewClient(modelRegistryURL)
+	c.features = ff
+	return c
+}
+
+// NewClientFromCluster creates a new Client like NewClient, but loads its feature flags from the
+// ai-edge-feature-flags ConfigMap in namespace via kubeConfig, falling back to features.DefaultFeatureFlags() if
+// the ConfigMap doesn't exist. This is the constructor that makes a cluster admin's central ConfigMap override
+// actually take effect; NewClient and NewClientWithFeatures never contact the cluster.
+func NewClientFromCluster(ctx context.Context, modelRegistryURL, namespace, kubeConfig string) (*Client, error) {
+	ff, err := features.Load(ctx, namespace, kubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
 	}
+	return NewClientWithFeatures(modelRegistryURL, ff), nil
 }
 
 // GetModels returns a list of models in the model registry.
@@ -86,6 +138,7 @@ func (c *Client) AddNewModelWithImage(
 	modelFormatName := "ContainerImage"
 	// This will be used to flag the model as edge compatible (i.e. has the required metadata to be built by the edge pipeline)
 	parameters["edgeCompatible"] = "true"
+	parameters[schemaVersionProperty] = CurrentSchemaVersion
 	md, err := modelregistry.ToMetadataValueMap(parameters)
 	if err != nil {
 		return nil, fmt.Errorf("failed to add model image: %w", err)
@@ -136,10 +189,10 @@ func (c *Client) GetModelImages() ([]ModelImage, error) {
 			if err != nil {
 				return nil, fmt.Errorf("failed to get model images: %w", err)
 			}
+			status := buildStatusFromParams(params)
+
 			if len(artifacts) > 0 {
 
-				// TODO: Set the status based on whether the image is built or not
-				// TODO: Figure out where to show the image SHA
 				// TODO: Figure out the URI
 				// REF: https://issues.redhat.com/browse/RHOAIENG-6628
 				for _, a := range artifacts {
@@ -150,6 +203,7 @@ func (c *Client) GetModelImages() ([]ModelImage, error) {
 						Version:     v.GetName(),
 						BuildParams: params,
 						URI:         a.ModelArtifact.GetUri(),
+						Status:      status,
 					}
 					images = append(images, i)
 				}
@@ -162,6 +216,7 @@ func (c *Client) GetModelImages() ([]ModelImage, error) {
 						Version:     v.GetName(),
 						BuildParams: params,
 						URI:         "",
+						Status:      status,
 					},
 				)
 			}
@@ -170,6 +225,82 @@ func (c *Client) GetModelImages() ([]ModelImage, error) {
 	return images, nil
 }
 
+// buildStatusFromParams reconstructs a BuildStatus from the model version custom properties mirrored by the
+// reporter subsystem, returning nil if no build has been reported for this version yet.
+func buildStatusFromParams(params map[string]interface{}) *BuildStatus {
+	status, ok := params["buildStatus"].(string)
+	if !ok {
+		return nil
+	}
+	bs := &BuildStatus{Status: status}
+	if s, ok := params["imageDigest"].(string); ok {
+		bs.ImageDigest = s
+	}
+	if s, ok := params["buildDuration"].(string); ok {
+		bs.BuildDuration = s
+	}
+	if s, ok := params["stepLogsRef"].(string); ok {
+		bs.StepLogsRef = s
+	}
+	return bs
+}
+
+// GetBuildStatus returns the state of a model version's image build, as mirrored into the model registry by the
+// reporter started with StartReporter. It returns a zero-value BuildStatus if no build has been reported yet.
+func (c *Client) GetBuildStatus(modelID, modelVersion string) (*BuildStatus, error) {
+	if modelID == "" || modelVersion == "" {
+		return nil, fmt.Errorf("model ID and model version are required")
+	}
+
+	v, err := c.modelRegistryClient.FindModelVersion(modelID, modelVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get build status: %w", err)
+	}
+	params, err := modelregistry.FromMetadataValueMap(v.GetCustomProperties())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get build status: %w", err)
+	}
+
+	status := buildStatusFromParams(params)
+	if status == nil {
+		status = &BuildStatus{}
+	}
+	return status, nil
+}
+
+// StartReporter starts a reconcile-style watch over aiedge-e2e PipelineRuns in namespaces, mirroring their
+// Running/Succeeded/Failed status, image digest, build duration, and a step logs pointer back into the model
+// registry as they change. This replaces the fire-and-forget behavior of CreatePipelineRun: GetModelImages and
+// GetBuildStatus read the state the reporter mirrors. Call StopReporter to stop watching.
+func (c *Client) StartReporter(ctx context.Context, kubeConfig string, namespaces []string) error {
+	if kubeConfig == "" || len(namespaces) == 0 {
+		return fmt.Errorf("kubeconfig and at least one namespace are required")
+	}
+
+	config, err := clientcmd.BuildConfigFromFlags("", kubeConfig)
+	if err != nil {
+		return fmt.Errorf("failed to start reporter: %w", err)
+	}
+	tektonClient, err := tektonclientset.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to start reporter: %w", err)
+	}
+
+	r := reporter.New(tektonClient, c.modelRegistryClient, namespaces)
+	if err := r.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start reporter: %w", err)
+	}
+	c.reporter = r
+	return nil
+}
+
+// StopReporter stops the reporter started by StartReporter. It is a no-op if no reporter is running.
+func (c *Client) StopReporter() {
+	if c.reporter != nil {
+		c.reporter.Stop()
+	}
+}
+
 // UpdateModelImage synchronizes edge model image information with the model registry by ensuring that the model
 // version and the model version artifact exist and are marked as edge compatible.
 //
@@ -200,12 +331,17 @@ func (c *Client) UpdateModelImage(
 // If parameters is nil, this method will try to use the custom properties of the model version if they exist.
 //
 // If parameters are provided they will be passed as parameters to the tekton PipelineRun.
+//
+// If pipelineSource is nil, the pipeline is resolved as a PipelineRef by name ("aiedge-e2e") in the target
+// namespace, as before. If pipelineSource is provided, it is used to resolve the pipeline via Tekton Resolvers
+// instead, e.g. to pin the build to a specific git revision or OCI bundle.
 func (c *Client) BuildModelImage(
 	modelID string,
 	modelVersion string,
 	namespace string,
 	kubeConfig string,
 	parameters map[string]interface{},
+	pipelineSource *PipelineSource,
 ) (*PipelineRun, error) {
 	if modelID == "" || modelVersion == "" || namespace == "" || kubeConfig == "" {
 		return nil, fmt.Errorf("model ID, model version, namespace, and kubeconfig are required")
@@ -226,7 +362,7 @@ func (c *Client) BuildModelImage(
 		}
 	}
 
-	return c.CreatePipelineRun(m.GetName(), modelVersion, namespace, kubeConfig, parameters)
+	return c.CreatePipelineRun(m.GetName(), modelVersion, namespace, kubeConfig, parameters, pipelineSource)
 }
 
 // ensureResourcesAreInModelRegistry ensures that the model version and the model version artifact are in the model
@@ -269,6 +405,7 @@ func (c *Client) ensureVersionIsInModelRegistry(
 				return nil, nil, fmt.Errorf("model version not found and no parameters provided")
 			}
 			parameters["edgeCompatible"] = "true"
+			parameters[schemaVersionProperty] = CurrentSchemaVersion
 			md, err := modelregistry.ToMetadataValueMap(parameters)
 			if err != nil {
 				return nil, nil, fmt.Errorf("failed to ensure version is in model registry: %w", err)
@@ -283,6 +420,7 @@ func (c *Client) ensureVersionIsInModelRegistry(
 	} else if parameters != nil {
 		// If the version is found and parameters are provided, we update the version with the parameters
 		parameters["edgeCompatible"] = "true"
+		parameters[schemaVersionProperty] = CurrentSchemaVersion
 		md, err := modelregistry.ToMetadataValueMap(parameters)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to ensure version is in model registry: %w", err)
@@ -323,12 +461,17 @@ func (c *Client) ensureArtifactIsInModelRegistry(modelVersionID, artifactName, d
 }
 
 // CreatePipelineRun creates a tekton PipelineRun to build a model container image from a model version artifact.
+//
+// If pipelineSource is nil, the aiedge-e2e pipeline is resolved as a PipelineRef by name in namespace. If
+// pipelineSource is provided, the pipeline is instead resolved via Tekton Resolvers (git, bundles, hub, cluster)
+// using pipelineSource.ResolverType and pipelineSource.Params.
 func (c *Client) CreatePipelineRun(
 	modelName string,
 	modelVersion string,
 	namespace string,
 	kubeConfig string,
 	parameters map[string]interface{},
+	pipelineSource *PipelineSource,
 ) (*PipelineRun, error) {
 	var s3SecretName string
 	var testDataConfigMapName string
@@ -349,20 +492,40 @@ func (c *Client) CreatePipelineRun(
 		testDataConfigMapName = tdc
 	}
 
+	if pipelineSource != nil && pipelineSource.ResolverType != "" {
+		if !c.features.EnableResolvers {
+			return nil, fmt.Errorf("resolver-based pipeline references are disabled (enable via the enable-resolvers feature flag)")
+		}
+		if pipelineSource.ResolverType == "bundles" && !c.features.EnableOCIBundleReferences {
+			return nil, fmt.Errorf("OCI bundle pipeline references are disabled (enable via the enable-oci-bundle-references feature flag)")
+		}
+	}
+
 	params, err := toTektonParams(modelName, modelVersion, parameters)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert parameters to tekton params: %w", err)
 	}
 
 	pipelineRun := newPipelineRunObject(
-		modelName, namespace, params, s3SecretName, testDataConfigMapName,
+		modelName, modelVersion, namespace, params, s3SecretName, testDataConfigMapName, pipelineSource,
 	)
 
 	config, _ := clientcmd.BuildConfigFromFlags("", kubeConfig)
 	tektonClient, _ := tektonclientset.NewForConfig(config)
 
+	ctx := context.Background()
+	if c.features.EnablePipelineVerification {
+		verification, err := c.verifyPipelineRef(ctx, tektonClient, namespace, kubeConfig, pipelineRun.Spec.PipelineRef)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify pipeline: %w", err)
+		}
+		if !verification.Verified {
+			return nil, fmt.Errorf("pipeline failed verification against enforced trust policies: %+v", verification.Failures)
+		}
+	}
+
 	createdPipelineRun, err := tektonClient.TektonV1().PipelineRuns(namespace).Create(
-		context.Background(), pipelineRun, metav1.CreateOptions{},
+		ctx, pipelineRun, metav1.CreateOptions{},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create pipeline run: %w", err)
@@ -373,12 +536,103 @@ func (c *Client) CreatePipelineRun(
 	}, nil
 }
 
+// GetPipelineRunStatus reads the named tekton PipelineRun and extracts provenance for the resolved aiedge-e2e
+// pipeline and for each of its TaskRuns' tasks, as reported by Tekton when the pipeline (or a task) was resolved
+// remotely via a Tekton Resolver. If the PipelineRun carries the "model-name"/"model-version" labels set by
+// CreatePipelineRun and the EnableProvenanceRecording feature flag is set, the provenance is also persisted onto the corresponding model version artifact's custom
+// properties (pipelineSourceURI, pipelineSourceDigest, pipelineSourceEntryPoint).
+func (c *Client) GetPipelineRunStatus(name, namespace, kubeConfig string) (*PipelineRun, error) {
+	if name == "" || namespace == "" || kubeConfig == "" {
+		return nil, fmt.Errorf("pipeline run name, namespace, and kubeconfig are required")
+	}
+
+	config, err := clientcmd.BuildConfigFromFlags("", kubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pipeline run status: %w", err)
+	}
+	tektonClient, err := tektonclientset.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pipeline run status: %w", err)
+	}
+
+	ctx := context.Background()
+	pr, err := tektonClient.TektonV1().PipelineRuns(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pipeline run status: %w", err)
+	}
+
+	provenance := provenanceFromPipelineRun(ctx, pr, tektonClient, namespace)
+
+	if modelName, modelVersion := pr.GetLabels()["model-name"], pr.GetLabels()["model-version"]; c.features.EnableProvenanceRecording && modelName != "" && modelVersion != "" {
+		if err := c.recordProvenance(modelName, modelVersion, provenance); err != nil {
+			return nil, fmt.Errorf("failed to get pipeline run status: %w", err)
+		}
+	}
+
+	return &PipelineRun{
+		Name:       pr.GetName(),
+		Namespace:  pr.GetNamespace(),
+		Provenance: provenance,
+	}, nil
+}
+
+// recordProvenance persists provenance onto the custom properties of the model version artifact for modelName and
+// modelVersion, merging it with whatever custom properties the artifact already has rather than replacing them.
+// It is a no-op if provenance has no pipeline source recorded, e.g. because the pipeline was referenced by name
+// rather than resolved remotely.
+func (c *Client) recordProvenance(modelName, modelVersion string, provenance *Provenance) error {
+	if provenance == nil || provenance.PipelineSourceURI == "" {
+		return nil
+	}
+
+	m, err := c.modelRegistryClient.FindRegisteredModelByName(modelName)
+	if err != nil {
+		return fmt.Errorf("failed to record provenance: %w", err)
+	}
+	v, err := c.modelRegistryClient.FindModelVersion(m.GetId(), modelVersion)
+	if err != nil {
+		return fmt.Errorf("failed to record provenance: %w", err)
+	}
+	artifact, err := c.modelRegistryClient.FindModelVersionArtifact(v.GetId(), m.GetName())
+	if err != nil {
+		return fmt.Errorf("failed to record provenance: %w", err)
+	}
+
+	customProperties, err := modelregistry.FromMetadataValueMap(artifact.GetCustomProperties())
+	if err != nil {
+		return fmt.Errorf("failed to record provenance: %w", err)
+	}
+
+	customProperties["pipelineSourceURI"] = provenance.PipelineSourceURI
+	customProperties["pipelineSourceDigest"] = provenance.PipelineSourceDigest
+	customProperties["pipelineSourceEntryPoint"] = provenance.PipelineSourceEntryPoint
+	if len(provenance.Tasks) > 0 {
+		taskProvenance, err := json.Marshal(provenance.Tasks)
+		if err != nil {
+			return fmt.Errorf("failed to record provenance: %w", err)
+		}
+		customProperties["taskProvenance"] = string(taskProvenance)
+	}
+
+	md, err := modelregistry.ToMetadataValueMap(customProperties)
+	if err != nil {
+		return fmt.Errorf("failed to record provenance: %w", err)
+	}
+
+	if _, err := c.modelRegistryClient.UpdateModelArtifact(artifact.GetId(), md); err != nil {
+		return fmt.Errorf("failed to record provenance: %w", err)
+	}
+	return nil
+}
+
 func newPipelineRunObject(
 	modelName string,
+	modelVersion string,
 	namespace string,
 	params tektonv1.Params,
 	s3SecretName string,
 	testDataConfigMapName string,
+	pipelineSource *PipelineSource,
 ) *tektonv1.PipelineRun {
 	pipelineRun := &tektonv1.PipelineRun{
 		ObjectMeta: metav1.ObjectMeta{
@@ -387,16 +641,15 @@ func newPipelineRunObject(
 			Labels: map[string]string{
 				"tekton.dev/pipeline": "aiedge-e2e",
 				"model-name":          modelName,
+				"model-version":       modelVersion,
 			},
 		},
 		Spec: tektonv1.PipelineRunSpec{
 			TaskRunTemplate: tektonv1.PipelineTaskRunTemplate{
 				ServiceAccountName: "pipeline",
 			},
-			Params: params,
-			PipelineRef: &tektonv1.PipelineRef{
-				Name: "aiedge-e2e",
-			},
+			Params:      params,
+			PipelineRef: toPipelineRef(pipelineSource),
 			Workspaces: []tektonv1.WorkspaceBinding{
 				{
 					Name: "build-workspace-pv",
@@ -431,6 +684,31 @@ func newPipelineRunObject(
 	return pipelineRun
 }
 
+// toPipelineRef builds the PipelineRef used to resolve the aiedge-e2e pipeline. If pipelineSource is nil, the
+// pipeline is referenced by name in the local namespace. Otherwise it is resolved via the Tekton Resolver named by
+// pipelineSource.ResolverType, with pipelineSource.Params passed through as resolver parameters.
+func toPipelineRef(pipelineSource *PipelineSource) *tektonv1.PipelineRef {
+	if pipelineSource == nil || pipelineSource.ResolverType == "" {
+		return &tektonv1.PipelineRef{
+			Name: "aiedge-e2e",
+		}
+	}
+
+	resolverParams := make(tektonv1.Params, 0, len(pipelineSource.Params))
+	for k, v := range pipelineSource.Params {
+		resolverParams = append(
+			resolverParams, tektonv1.Param{Name: k, Value: *tektonv1.NewStructuredValues(v)},
+		)
+	}
+
+	return &tektonv1.PipelineRef{
+		ResolverRef: tektonv1.ResolverRef{
+			Resolver: tektonv1.ResolverName(pipelineSource.ResolverType),
+			Params:   resolverParams,
+		},
+	}
+}
+
 func toTektonParams(modelName, modelVersion string, parameters map[string]interface{}) (tektonv1.Params, error) {
 	params := tektonv1.Params{
 		{
@@ -0,0 +1,87 @@
+/*
+Copyright 2024. Open Data Hub Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edgeclient
+
+import (
+	"testing"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+func TestToPipelineRef(t *testing.T) {
+	cases := []struct {
+		name           string
+		pipelineSource *PipelineSource
+		wantName       string
+		wantResolver   tektonv1.ResolverName
+		wantParams     map[string]string
+	}{
+		{
+			name:     "nil source references the pipeline by name",
+			wantName: "aiedge-e2e",
+		},
+		{
+			name:           "empty resolver type references the pipeline by name",
+			pipelineSource: &PipelineSource{},
+			wantName:       "aiedge-e2e",
+		},
+		{
+			name: "resolver type resolves via a Tekton Resolver",
+			pipelineSource: &PipelineSource{
+				ResolverType: "git",
+				Params:       map[string]string{"url": "https://example.com/repo.git", "revision": "main"},
+			},
+			wantResolver: "git",
+			wantParams:   map[string]string{"url": "https://example.com/repo.git", "revision": "main"},
+		},
+		{
+			name: "bundles resolver type",
+			pipelineSource: &PipelineSource{
+				ResolverType: "bundles",
+				Params:       map[string]string{"bundle": "quay.io/example/bundle:v1"},
+			},
+			wantResolver: "bundles",
+			wantParams:   map[string]string{"bundle": "quay.io/example/bundle:v1"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(
+			tc.name, func(t *testing.T) {
+				ref := toPipelineRef(tc.pipelineSource)
+
+				if ref.Name != tc.wantName {
+					t.Errorf("Name = %q, want %q", ref.Name, tc.wantName)
+				}
+				if tc.wantResolver == "" {
+					return
+				}
+				if ref.ResolverRef.Resolver != tc.wantResolver {
+					t.Errorf("Resolver = %q, want %q", ref.ResolverRef.Resolver, tc.wantResolver)
+				}
+				if len(ref.ResolverRef.Params) != len(tc.wantParams) {
+					t.Fatalf("len(Params) = %d, want %d", len(ref.ResolverRef.Params), len(tc.wantParams))
+				}
+				for _, p := range ref.ResolverRef.Params {
+					if want, ok := tc.wantParams[p.Name]; !ok || p.Value.StringVal != want {
+						t.Errorf("Params[%q] = %q, want %q", p.Name, p.Value.StringVal, want)
+					}
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2024. Open Data Hub Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package features gates experimental edgeclient behavior behind feature flags, following the pattern of
+// Tekton's own feature-flags ConfigMap: defaults are baked in, and a cluster admin can override them centrally
+// via a ConfigMap without every caller needing to know about the override.
+package features
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// ConfigMapName is the name of the ConfigMap that holds cluster-wide feature flag overrides for the edge client.
+const ConfigMapName = "ai-edge-feature-flags"
+
+// FeatureFlags gates experimental edgeclient behavior so it can be adopted incrementally by users and disabled
+// centrally by admins.
+type FeatureFlags struct {
+	// EnableResolvers allows the aiedge-e2e pipeline to be fetched via Tekton Resolvers (git, hub, cluster)
+	// instead of always referencing it by name in the local namespace.
+	EnableResolvers bool
+	// EnablePipelineVerification verifies the resolved pipeline, and the tasks it references, against
+	// VerificationPolicies before a PipelineRun is created.
+	EnablePipelineVerification bool
+	// EnableOCIBundleReferences allows the "bundles" resolver specifically, letting PipelineSource pin the
+	// pipeline to an OCI bundle. It has no effect unless EnableResolvers is also set.
+	EnableOCIBundleReferences bool
+	// EnableProvenanceRecording records pipeline/task provenance onto the model version artifact that a build
+	// produced.
+	EnableProvenanceRecording bool
+}
+
+// DefaultFeatureFlags are the feature flag values used when no ConfigMap override is found.
+func DefaultFeatureFlags() FeatureFlags {
+	return FeatureFlags{
+		EnableResolvers:            false,
+		EnablePipelineVerification: false,
+		EnableOCIBundleReferences:  false,
+		EnableProvenanceRecording:  true,
+	}
+}
+
+// knownKeys maps each accepted ai-edge-feature-flags ConfigMap key to the setter it controls. apply rejects any
+// key not in this set.
+var knownKeys = map[string]func(*FeatureFlags, bool){
+	"enable-resolvers":             func(ff *FeatureFlags, v bool) { ff.EnableResolvers = v },
+	"enable-pipeline-verification": func(ff *FeatureFlags, v bool) { ff.EnablePipelineVerification = v },
+	"enable-oci-bundle-references": func(ff *FeatureFlags, v bool) { ff.EnableOCIBundleReferences = v },
+	"enable-provenance-recording":  func(ff *FeatureFlags, v bool) { ff.EnableProvenanceRecording = v },
+}
+
+// Load returns the FeatureFlags for namespace, starting from DefaultFeatureFlags and applying any overrides found
+// in the ai-edge-feature-flags ConfigMap. If kubeConfig is empty, or the ConfigMap does not exist, the defaults
+// are returned unchanged.
+func Load(ctx context.Context, namespace, kubeConfig string) (FeatureFlags, error) {
+	ff := DefaultFeatureFlags()
+	if kubeConfig == "" {
+		return ff, nil
+	}
+
+	config, err := clientcmd.BuildConfigFromFlags("", kubeConfig)
+	if err != nil {
+		return ff, fmt.Errorf("failed to load feature flags: %w", err)
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return ff, fmt.Errorf("failed to load feature flags: %w", err)
+	}
+
+	cm, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, ConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ff, nil
+		}
+		return ff, fmt.Errorf("failed to load feature flags: %w", err)
+	}
+
+	if err := apply(&ff, cm.Data); err != nil {
+		return ff, fmt.Errorf("failed to load feature flags: %w", err)
+	}
+	return ff, nil
+}
+
+// apply validates and applies ConfigMap data onto ff, rejecting any key not in knownKeys or any value that
+// doesn't parse as a boolean.
+func apply(ff *FeatureFlags, data map[string]string) error {
+	for k, v := range data {
+		setter, ok := knownKeys[k]
+		if !ok {
+			return fmt.Errorf("unknown feature flag %q", k)
+		}
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("feature flag %q has invalid boolean value %q: %w", k, v, err)
+		}
+		setter(ff, enabled)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024. Open Data Hub Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import "testing"
+
+func TestApply(t *testing.T) {
+	cases := []struct {
+		name    string
+		data    map[string]string
+		want    FeatureFlags
+		wantErr bool
+	}{
+		{
+			name: "empty data keeps defaults",
+			data: map[string]string{},
+			want: DefaultFeatureFlags(),
+		},
+		{
+			name: "enables resolvers only",
+			data: map[string]string{"enable-resolvers": "true"},
+			want: FeatureFlags{EnableResolvers: true, EnableProvenanceRecording: true},
+		},
+		{
+			name: "enables every flag",
+			data: map[string]string{
+				"enable-resolvers":             "true",
+				"enable-pipeline-verification": "true",
+				"enable-oci-bundle-references": "true",
+				"enable-provenance-recording":  "true",
+			},
+			want: FeatureFlags{
+				EnableResolvers:            true,
+				EnablePipelineVerification: true,
+				EnableOCIBundleReferences:  true,
+				EnableProvenanceRecording:  true,
+			},
+		},
+		{
+			name: "disables the default-on flag",
+			data: map[string]string{"enable-provenance-recording": "false"},
+			want: FeatureFlags{EnableProvenanceRecording: false},
+		},
+		{
+			name:    "rejects an unknown key",
+			data:    map[string]string{"enable-something-else": "true"},
+			wantErr: true,
+		},
+		{
+			name:    "rejects a non-boolean value",
+			data:    map[string]string{"enable-resolvers": "yes"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(
+			tc.name, func(t *testing.T) {
+				ff := DefaultFeatureFlags()
+				err := apply(&ff, tc.data)
+				if tc.wantErr {
+					if err == nil {
+						t.Fatalf("apply(%v) = nil error, want error", tc.data)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("apply(%v) = %v, want no error", tc.data, err)
+				}
+				if ff != tc.want {
+					t.Fatalf("apply(%v) = %+v, want %+v", tc.data, ff, tc.want)
+				}
+			},
+		)
+	}
+}
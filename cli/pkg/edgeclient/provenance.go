@@ -0,0 +1,90 @@
+/*
+Copyright 2024. Open Data Hub Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edgeclient
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	tektonclientset "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Provenance records the ConfigSource of the resolved aiedge-e2e pipeline, and of the tasks it ran, that produced
+// a model container image. It is only populated when the pipeline (or a task) was resolved remotely via a Tekton
+// Resolver, giving edge operators an auditable record of exactly which pipeline/task revisions produced the image.
+type Provenance struct {
+	PipelineSourceURI        string
+	PipelineSourceDigest     string
+	PipelineSourceEntryPoint string
+	Tasks                    []TaskProvenance
+}
+
+// TaskProvenance records the ConfigSource of a single resolved task within a PipelineRun.
+type TaskProvenance struct {
+	TaskName     string
+	SourceURI    string
+	SourceDigest string
+	EntryPoint   string
+}
+
+// provenanceFromPipelineRun extracts provenance from a PipelineRun's status: the ConfigSource of the pipeline
+// itself, plus the ConfigSource of every child TaskRun's task that Tekton recorded provenance for.
+func provenanceFromPipelineRun(
+	ctx context.Context, pr *tektonv1.PipelineRun, tektonClient tektonclientset.Interface, namespace string,
+) *Provenance {
+	provenance := &Provenance{}
+	if pr.Status.Provenance != nil && pr.Status.Provenance.ConfigSource != nil {
+		cs := pr.Status.Provenance.ConfigSource
+		provenance.PipelineSourceURI = cs.URI
+		provenance.PipelineSourceDigest = digestString(cs.Digest)
+		provenance.PipelineSourceEntryPoint = cs.EntryPoint
+	}
+
+	for _, child := range pr.Status.ChildReferences {
+		tr, err := tektonClient.TektonV1().TaskRuns(namespace).Get(ctx, child.Name, metav1.GetOptions{})
+		if err != nil || tr.Status.Provenance == nil || tr.Status.Provenance.ConfigSource == nil {
+			continue
+		}
+		cs := tr.Status.Provenance.ConfigSource
+		provenance.Tasks = append(
+			provenance.Tasks, TaskProvenance{
+				TaskName:     child.PipelineTaskName,
+				SourceURI:    cs.URI,
+				SourceDigest: digestString(cs.Digest),
+				EntryPoint:   cs.EntryPoint,
+			},
+		)
+	}
+	return provenance
+}
+
+// digestString renders a Tekton ConfigSource digest (algorithm -> hex value) as a single "alg:hex" string,
+// joining multiple algorithms with a comma so it can be stored as a plain custom property string.
+func digestString(digest map[string]string) string {
+	if len(digest) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(digest))
+	for alg, hex := range digest {
+		parts = append(parts, alg+":"+hex)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
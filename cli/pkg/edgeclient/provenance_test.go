@@ -0,0 +1,131 @@
+/*
+Copyright 2024. Open Data Hub Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edgeclient
+
+import (
+	"context"
+	"testing"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	tektonfake "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDigestString(t *testing.T) {
+	cases := []struct {
+		name   string
+		digest map[string]string
+		want   string
+	}{
+		{name: "nil digest", digest: nil, want: ""},
+		{name: "single algorithm", digest: map[string]string{"sha256": "abc"}, want: "sha256:abc"},
+		{
+			name:   "multiple algorithms are sorted for a stable string",
+			digest: map[string]string{"sha512": "def", "sha256": "abc"},
+			want:   "sha256:abc,sha512:def",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(
+			tc.name, func(t *testing.T) {
+				if got := digestString(tc.digest); got != tc.want {
+					t.Errorf("digestString(%v) = %q, want %q", tc.digest, got, tc.want)
+				}
+			},
+		)
+	}
+}
+
+func TestProvenanceFromPipelineRun(t *testing.T) {
+	taskRun := &tektonv1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "build-task-run", Namespace: "ns"},
+		Status: tektonv1.TaskRunStatus{
+			TaskRunStatusFields: tektonv1.TaskRunStatusFields{
+				Provenance: &tektonv1.Provenance{
+					ConfigSource: &tektonv1.ConfigSource{
+						URI:        "git+https://example.com/tasks.git",
+						Digest:     map[string]string{"sha256": "task-digest"},
+						EntryPoint: "build.yaml",
+					},
+				},
+			},
+		},
+	}
+
+	pr := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "run", Namespace: "ns"},
+		Status: tektonv1.PipelineRunStatus{
+			PipelineRunStatusFields: tektonv1.PipelineRunStatusFields{
+				Provenance: &tektonv1.Provenance{
+					ConfigSource: &tektonv1.ConfigSource{
+						URI:        "git+https://example.com/pipeline.git",
+						Digest:     map[string]string{"sha256": "pipeline-digest"},
+						EntryPoint: "pipeline.yaml",
+					},
+				},
+				ChildReferences: []tektonv1.ChildStatusReference{
+					{Name: "build-task-run", PipelineTaskName: "build"},
+				},
+			},
+		},
+	}
+
+	client := tektonfake.NewSimpleClientset(taskRun)
+
+	provenance := provenanceFromPipelineRun(context.Background(), pr, client, "ns")
+
+	if provenance.PipelineSourceURI != "git+https://example.com/pipeline.git" {
+		t.Errorf("PipelineSourceURI = %q", provenance.PipelineSourceURI)
+	}
+	if provenance.PipelineSourceDigest != "sha256:pipeline-digest" {
+		t.Errorf("PipelineSourceDigest = %q", provenance.PipelineSourceDigest)
+	}
+	if provenance.PipelineSourceEntryPoint != "pipeline.yaml" {
+		t.Errorf("PipelineSourceEntryPoint = %q", provenance.PipelineSourceEntryPoint)
+	}
+	if len(provenance.Tasks) != 1 {
+		t.Fatalf("len(Tasks) = %d, want 1", len(provenance.Tasks))
+	}
+	task := provenance.Tasks[0]
+	if task.TaskName != "build" ||
+		task.SourceURI != "git+https://example.com/tasks.git" ||
+		task.SourceDigest != "sha256:task-digest" ||
+		task.EntryPoint != "build.yaml" {
+		t.Errorf("Tasks[0] = %+v", task)
+	}
+}
+
+func TestProvenanceFromPipelineRun_MissingTaskRunIsSkipped(t *testing.T) {
+	pr := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "run", Namespace: "ns"},
+		Status: tektonv1.PipelineRunStatus{
+			PipelineRunStatusFields: tektonv1.PipelineRunStatusFields{
+				ChildReferences: []tektonv1.ChildStatusReference{
+					{Name: "missing-task-run", PipelineTaskName: "build"},
+				},
+			},
+		},
+	}
+
+	client := tektonfake.NewSimpleClientset()
+
+	provenance := provenanceFromPipelineRun(context.Background(), pr, client, "ns")
+	if len(provenance.Tasks) != 0 {
+		t.Errorf("Tasks = %+v, want none", provenance.Tasks)
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2024. Open Data Hub Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package reporter runs a reconcile-style watch over aiedge-e2e PipelineRuns and mirrors their state back into
+// the model registry, replacing the fire-and-forget behavior of edgeclient.Client.CreatePipelineRun.
+package reporter
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	tektonclientset "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	tektoninformers "github.com/tektoncd/pipeline/pkg/client/informers/externalversions"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/apis"
+
+	"github.com/opendatahub-io/ai-edge/cli/pkg/modelregistry"
+)
+
+// pipelineLabelSelector restricts the reporter's informers to PipelineRuns created for the aiedge-e2e pipeline.
+const pipelineLabelSelector = "tekton.dev/pipeline=aiedge-e2e"
+
+// resyncPeriod is how often the informers re-list, on top of watching for live updates.
+const resyncPeriod = 30 * time.Minute
+
+// Reporter watches PipelineRuns labeled tekton.dev/pipeline=aiedge-e2e across a set of namespaces and mirrors
+// their Running/Succeeded/Failed status, image digest, build duration, and a step logs pointer back into the
+// model registry as custom properties on the corresponding model version.
+type Reporter struct {
+	registry  *modelregistry.Client
+	informers []cache.SharedIndexInformer
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// New creates a Reporter that will watch PipelineRuns in namespaces once Start is called.
+func New(tektonClient tektonclientset.Interface, registry *modelregistry.Client, namespaces []string) *Reporter {
+	r := &Reporter{
+		registry: registry,
+		stopCh:   make(chan struct{}),
+	}
+	for _, ns := range namespaces {
+		factory := tektoninformers.NewSharedInformerFactoryWithOptions(
+			tektonClient, resyncPeriod,
+			tektoninformers.WithNamespace(ns),
+			tektoninformers.WithTweakListOptions(
+				func(opts *metav1.ListOptions) { opts.LabelSelector = pipelineLabelSelector },
+			),
+		)
+		informer := factory.Tekton().V1().PipelineRuns().Informer()
+		informer.AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    r.handle,
+				UpdateFunc: func(_, obj interface{}) { r.handle(obj) },
+			},
+		)
+		r.informers = append(r.informers, informer)
+	}
+	return r
+}
+
+// Start begins watching PipelineRuns in the configured namespaces, blocking until the informer caches have
+// synced or ctx is done. Call Stop, or cancel ctx, to stop watching.
+func (r *Reporter) Start(ctx context.Context) error {
+	go func() {
+		select {
+		case <-ctx.Done():
+			r.Stop()
+		case <-r.stopCh:
+		}
+	}()
+
+	for _, informer := range r.informers {
+		go informer.Run(r.stopCh)
+	}
+	for _, informer := range r.informers {
+		if !cache.WaitForCacheSync(r.stopCh, informer.HasSynced) {
+			return fmt.Errorf("failed to sync pipeline run informer cache")
+		}
+	}
+	return nil
+}
+
+// Stop stops all informers started by Start. It is safe to call more than once.
+func (r *Reporter) Stop() {
+	r.stopOnce.Do(func() { close(r.stopCh) })
+}
+
+// handle mirrors a single PipelineRun's state into the model registry. PipelineRuns without the "model-name" and
+// "model-version" labels set by edgeclient.CreatePipelineRun are ignored, since there is no model version to
+// update them against.
+func (r *Reporter) handle(obj interface{}) {
+	pr, ok := obj.(*tektonv1.PipelineRun)
+	if !ok {
+		return
+	}
+
+	modelName := pr.GetLabels()["model-name"]
+	modelVersion := pr.GetLabels()["model-version"]
+	if modelName == "" || modelVersion == "" {
+		return
+	}
+
+	props := map[string]interface{}{
+		"buildStatus": buildStatus(pr),
+		"stepLogsRef": fmt.Sprintf("namespace/%s/pipelinerun/%s", pr.GetNamespace(), pr.GetName()),
+	}
+	if digest := imageDigest(pr); digest != "" {
+		props["imageDigest"] = digest
+	}
+	if duration := buildDuration(pr); duration != "" {
+		props["buildDuration"] = duration
+	}
+
+	if err := r.updateModelVersion(modelName, modelVersion, props); err != nil {
+		// Best-effort: a transient model registry error here shouldn't take down the reporter. The informer
+		// will redeliver the next update for this PipelineRun, which will retry the write.
+		log.Printf("ai-edge reporter: failed to mirror pipeline run %s/%s onto %s/%s: %v", pr.GetNamespace(), pr.GetName(), modelName, modelVersion, err)
+	}
+}
+
+// updateModelVersion merges props into the model version's existing custom properties and writes them back.
+func (r *Reporter) updateModelVersion(modelName, modelVersion string, props map[string]interface{}) error {
+	m, err := r.registry.FindRegisteredModelByName(modelName)
+	if err != nil {
+		return fmt.Errorf("failed to find model %s: %w", modelName, err)
+	}
+	v, err := r.registry.FindModelVersion(m.GetId(), modelVersion)
+	if err != nil {
+		return fmt.Errorf("failed to find model version %s/%s: %w", modelName, modelVersion, err)
+	}
+
+	customProperties, err := modelregistry.FromMetadataValueMap(v.GetCustomProperties())
+	if err != nil {
+		return fmt.Errorf("failed to read existing custom properties: %w", err)
+	}
+	for k, val := range props {
+		customProperties[k] = val
+	}
+
+	md, err := modelregistry.ToMetadataValueMap(customProperties)
+	if err != nil {
+		return fmt.Errorf("failed to convert custom properties: %w", err)
+	}
+	if _, err := r.registry.UpdateModelVersion(v.GetId(), md); err != nil {
+		return fmt.Errorf("failed to update model version: %w", err)
+	}
+	return nil
+}
+
+// buildStatus maps a PipelineRun's Succeeded condition onto "Running", "Succeeded", or "Failed".
+func buildStatus(pr *tektonv1.PipelineRun) string {
+	cond := pr.Status.GetCondition(apis.ConditionSucceeded)
+	if cond == nil {
+		return "Running"
+	}
+	switch cond.Status {
+	case corev1.ConditionTrue:
+		return "Succeeded"
+	case corev1.ConditionFalse:
+		return "Failed"
+	default:
+		return "Running"
+	}
+}
+
+// imageDigest returns the aiedge-e2e pipeline's "image-digest" result, if it has been produced yet.
+func imageDigest(pr *tektonv1.PipelineRun) string {
+	for _, result := range pr.Status.Results {
+		if result.Name == "image-digest" {
+			return result.Value.StringVal
+		}
+	}
+	return ""
+}
+
+// buildDuration returns the PipelineRun's elapsed build time once it has both started and completed.
+func buildDuration(pr *tektonv1.PipelineRun) string {
+	if pr.Status.StartTime == nil || pr.Status.CompletionTime == nil {
+		return ""
+	}
+	return pr.Status.CompletionTime.Sub(pr.Status.StartTime.Time).String()
+}
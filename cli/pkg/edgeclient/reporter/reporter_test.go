@@ -0,0 +1,131 @@
+/*
+Copyright 2024. Open Data Hub Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reporter
+
+import (
+	"testing"
+	"time"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+func TestBuildStatus(t *testing.T) {
+	cases := []struct {
+		name string
+		cond *apis.Condition
+		want string
+	}{
+		{name: "no condition yet is running", cond: nil, want: "Running"},
+		{
+			name: "succeeded condition true",
+			cond: &apis.Condition{Type: apis.ConditionSucceeded, Status: corev1.ConditionTrue},
+			want: "Succeeded",
+		},
+		{
+			name: "succeeded condition false",
+			cond: &apis.Condition{Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse},
+			want: "Failed",
+		},
+		{
+			name: "succeeded condition unknown is still running",
+			cond: &apis.Condition{Type: apis.ConditionSucceeded, Status: corev1.ConditionUnknown},
+			want: "Running",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(
+			tc.name, func(t *testing.T) {
+				pr := &tektonv1.PipelineRun{}
+				if tc.cond != nil {
+					pr.Status.SetCondition(tc.cond)
+				}
+				if got := buildStatus(pr); got != tc.want {
+					t.Errorf("buildStatus() = %q, want %q", got, tc.want)
+				}
+			},
+		)
+	}
+}
+
+func TestImageDigest(t *testing.T) {
+	cases := []struct {
+		name    string
+		results []tektonv1.PipelineRunResult
+		want    string
+	}{
+		{name: "no results yet", results: nil, want: ""},
+		{
+			name: "image-digest result present",
+			results: []tektonv1.PipelineRunResult{
+				{Name: "image-digest", Value: *tektonv1.NewStructuredValues("sha256:abc")},
+			},
+			want: "sha256:abc",
+		},
+		{
+			name: "unrelated results are ignored",
+			results: []tektonv1.PipelineRunResult{
+				{Name: "some-other-result", Value: *tektonv1.NewStructuredValues("irrelevant")},
+			},
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(
+			tc.name, func(t *testing.T) {
+				pr := &tektonv1.PipelineRun{}
+				pr.Status.Results = tc.results
+				if got := imageDigest(pr); got != tc.want {
+					t.Errorf("imageDigest() = %q, want %q", got, tc.want)
+				}
+			},
+		)
+	}
+}
+
+func TestBuildDuration(t *testing.T) {
+	start := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(90 * time.Second))
+
+	cases := []struct {
+		name  string
+		start *metav1.Time
+		end   *metav1.Time
+		want  string
+	}{
+		{name: "not started yet", start: nil, end: nil, want: ""},
+		{name: "started but not completed", start: &start, end: nil, want: ""},
+		{name: "started and completed", start: &start, end: &end, want: "1m30s"},
+	}
+
+	for _, tc := range cases {
+		t.Run(
+			tc.name, func(t *testing.T) {
+				pr := &tektonv1.PipelineRun{}
+				pr.Status.StartTime = tc.start
+				pr.Status.CompletionTime = tc.end
+				if got := buildDuration(pr); got != tc.want {
+					t.Errorf("buildDuration() = %q, want %q", got, tc.want)
+				}
+			},
+		)
+	}
+}
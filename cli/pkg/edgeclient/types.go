@@ -0,0 +1,57 @@
+/*
+Copyright 2024. Open Data Hub Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edgeclient
+
+// Model represents a model registered in the model registry.
+type Model struct {
+	ID          string
+	Name        string
+	Description string
+}
+
+// ModelImage represents a model container image, along with the model registry metadata that describes it.
+type ModelImage struct {
+	ModelID     string
+	Name        string
+	Description string
+	Version     string
+	BuildParams map[string]interface{}
+	URI         string
+	// Provenance records where the pipeline (and tasks) that built this image came from, when known.
+	Provenance *Provenance
+	// Status is the state of this image's build, as mirrored into the model registry by the reporter subsystem.
+	// It is nil if no build has been reported yet.
+	Status *BuildStatus
+}
+
+// PipelineRun represents a tekton PipelineRun that builds a model container image.
+type PipelineRun struct {
+	Name      string
+	Namespace string
+	// Provenance records where the pipeline (and tasks) that produced this run came from, when known.
+	Provenance *Provenance
+}
+
+// BuildStatus is the state of a model version's image build, as mirrored into the model registry by the
+// reporter subsystem. Status is one of "Running", "Succeeded", or "Failed"; it is empty if no build has been
+// reported yet.
+type BuildStatus struct {
+	Status        string
+	ImageDigest   string
+	BuildDuration string
+	StepLogsRef   string
+}
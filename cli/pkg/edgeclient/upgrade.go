@@ -0,0 +1,201 @@
+/*
+Copyright 2024. Open Data Hub Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edgeclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kubeflow/model-registry/pkg/openapi"
+
+	"github.com/opendatahub-io/ai-edge/cli/pkg/modelregistry"
+)
+
+// schemaVersionProperty is the model version custom property that stamps the edgeClientSchemaVersion a model
+// version's custom properties currently conform to, so MigrateAll knows which migrations still need to run.
+const schemaVersionProperty = "edgeClientSchemaVersion"
+
+// unstampedSchemaVersion is the implicit schema version of a model version with no schemaVersionProperty, i.e.
+// one created before this client started stamping versions.
+const unstampedSchemaVersion = "1"
+
+// CurrentSchemaVersion is the schema version this client stamps new model versions with.
+const CurrentSchemaVersion = "2"
+
+// Migration upgrades a model version's custom properties from schema version From to schema version To.
+//
+// Apply's signature is deliberately narrower than the general shape of a model-registry operation (e.g.
+// func(ctx, *modelregistry.Client, *openapi.ModelVersion) error): every migration registered so far is a pure
+// transform of one version's custom properties, with no need to read or write the registry or any other resource
+// directly. migrateVersion owns the single read-migrate-write round trip instead, so a Migration can't leave a
+// version partially updated or forget to persist its result. If a future migration genuinely needs registry or
+// artifact access, widen this signature then rather than threading unused parameters through every migration now.
+type Migration struct {
+	From  string
+	To    string
+	Apply func(ctx context.Context, customProperties map[string]interface{}) error
+}
+
+// migrations are the registered Migrations. Use RegisterMigration to add to this list.
+var migrations []Migration
+
+// RegisterMigration adds m to the set of migrations MigrateAll will consider applying.
+func RegisterMigration(m Migration) {
+	migrations = append(migrations, m)
+}
+
+func init() {
+	RegisterMigration(
+		Migration{
+			From:  unstampedSchemaVersion,
+			To:    CurrentSchemaVersion,
+			Apply: migrateNormalizeLegacyParameterKeys,
+		},
+	)
+}
+
+// MigrateAll iterates every registered model and model version, applies whichever registered migrations bring its
+// custom properties up to CurrentSchemaVersion, and atomically updates the edgeClientSchemaVersion stamp together
+// with the migrated properties in a single write per version.
+func (c *Client) MigrateAll(ctx context.Context) error {
+	byFrom, err := migrationPath()
+	if err != nil {
+		return fmt.Errorf("failed to migrate: %w", err)
+	}
+
+	models, err := c.modelRegistryClient.GetRegisteredModels()
+	if err != nil {
+		return fmt.Errorf("failed to migrate: %w", err)
+	}
+	for _, m := range models {
+		versions, err := c.modelRegistryClient.GetModelVersions(m.GetId())
+		if err != nil {
+			return fmt.Errorf("failed to migrate model %s: %w", m.GetName(), err)
+		}
+		for _, v := range versions {
+			if err := c.migrateVersion(ctx, v, byFrom); err != nil {
+				return fmt.Errorf("failed to migrate model %s version %s: %w", m.GetName(), v.GetName(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// migrationPath indexes the registered migrations by the schema version they upgrade from, so migrateVersion can
+// walk the chain a given version needs. It returns an error if two migrations claim the same starting version, or
+// if following the chain from any registered starting point cycles back on itself.
+func migrationPath() (map[string]Migration, error) {
+	byFrom := make(map[string]Migration, len(migrations))
+	for _, m := range migrations {
+		if _, dup := byFrom[m.From]; dup {
+			return nil, fmt.Errorf("multiple migrations registered from schema version %s", m.From)
+		}
+		byFrom[m.From] = m
+	}
+
+	for from := range byFrom {
+		seen := map[string]bool{}
+		for cur := from; ; {
+			if seen[cur] {
+				return nil, fmt.Errorf("migration chain starting at schema version %s cycles back on itself", from)
+			}
+			seen[cur] = true
+			next, ok := byFrom[cur]
+			if !ok {
+				break
+			}
+			cur = next.To
+		}
+	}
+	return byFrom, nil
+}
+
+// migrateVersion applies every migration in byFrom that v's current schema version leads into, in order, and
+// writes the result back in a single UpdateModelVersion call. It is a no-op if v is already at a schema version
+// with no further migration registered.
+func (c *Client) migrateVersion(ctx context.Context, v openapi.ModelVersion, byFrom map[string]Migration) error {
+	props, err := modelregistry.FromMetadataValueMap(v.GetCustomProperties())
+	if err != nil {
+		return fmt.Errorf("failed to read custom properties: %w", err)
+	}
+
+	version := schemaVersionOf(props)
+	applied := false
+	for {
+		m, ok := byFrom[version]
+		if !ok {
+			break
+		}
+		if err := m.Apply(ctx, props); err != nil {
+			return fmt.Errorf("migration %s -> %s failed: %w", m.From, m.To, err)
+		}
+		version = m.To
+		applied = true
+	}
+	if !applied {
+		return nil
+	}
+
+	props[schemaVersionProperty] = version
+	md, err := modelregistry.ToMetadataValueMap(props)
+	if err != nil {
+		return fmt.Errorf("failed to convert custom properties: %w", err)
+	}
+	if _, err := c.modelRegistryClient.UpdateModelVersion(v.GetId(), md); err != nil {
+		return fmt.Errorf("failed to update model version: %w", err)
+	}
+	return nil
+}
+
+// schemaVersionOf returns the schema version stamped on props, or unstampedSchemaVersion if none is set.
+func schemaVersionOf(props map[string]interface{}) string {
+	if s, ok := props[schemaVersionProperty].(string); ok && s != "" {
+		return s
+	}
+	return unstampedSchemaVersion
+}
+
+// migrateNormalizeLegacyParameterKeys is the unstampedSchemaVersion -> CurrentSchemaVersion migration. Early CLI
+// versions stored edge compatibility under inconsistent casings (e.g. "EdgeCompatible", "edge_compatible") and
+// combined the S3 secret and test data ConfigMap into a single "s3Connection" parameter of the form
+// "<secretName>/<configMapName>". This normalizes both onto the keys the current pipeline parameter schema
+// expects: "edgeCompatible", "s3SecretName", and "testDataConfigMapName".
+func migrateNormalizeLegacyParameterKeys(_ context.Context, customProperties map[string]interface{}) error {
+	for _, legacyKey := range []string{"EdgeCompatible", "edge_compatible"} {
+		if v, ok := customProperties[legacyKey]; ok {
+			delete(customProperties, legacyKey)
+			customProperties["edgeCompatible"] = v
+		}
+	}
+
+	if combined, ok := customProperties["s3Connection"].(string); ok {
+		parts := strings.SplitN(combined, "/", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("legacy s3Connection parameter %q is not in <secretName>/<configMapName> form", combined)
+		}
+		delete(customProperties, "s3Connection")
+		if _, exists := customProperties["s3SecretName"]; !exists {
+			customProperties["s3SecretName"] = parts[0]
+		}
+		if _, exists := customProperties["testDataConfigMapName"]; !exists {
+			customProperties["testDataConfigMapName"] = parts[1]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024. Open Data Hub Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edgeclient
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestMigrateNormalizeLegacyParameterKeys(t *testing.T) {
+	cases := []struct {
+		name       string
+		properties map[string]interface{}
+		want       map[string]interface{}
+		wantErr    bool
+	}{
+		{
+			name:       "already-normalized properties are left alone",
+			properties: map[string]interface{}{"edgeCompatible": "true"},
+			want:       map[string]interface{}{"edgeCompatible": "true"},
+		},
+		{
+			name:       "PascalCase legacy key is renamed",
+			properties: map[string]interface{}{"EdgeCompatible": "true"},
+			want:       map[string]interface{}{"edgeCompatible": "true"},
+		},
+		{
+			name:       "snake_case legacy key is renamed",
+			properties: map[string]interface{}{"edge_compatible": "true"},
+			want:       map[string]interface{}{"edgeCompatible": "true"},
+		},
+		{
+			name:       "combined s3Connection is split into its two parameters",
+			properties: map[string]interface{}{"s3Connection": "my-secret/my-configmap"},
+			want: map[string]interface{}{
+				"s3SecretName":          "my-secret",
+				"testDataConfigMapName": "my-configmap",
+			},
+		},
+		{
+			name: "s3Connection split does not clobber already-set values",
+			properties: map[string]interface{}{
+				"s3Connection": "legacy-secret/legacy-configmap",
+				"s3SecretName": "current-secret",
+			},
+			want: map[string]interface{}{
+				"s3SecretName":          "current-secret",
+				"testDataConfigMapName": "legacy-configmap",
+			},
+		},
+		{
+			name:       "malformed s3Connection is an error",
+			properties: map[string]interface{}{"s3Connection": "no-slash-here"},
+			wantErr:    true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(
+			tc.name, func(t *testing.T) {
+				err := migrateNormalizeLegacyParameterKeys(context.Background(), tc.properties)
+				if tc.wantErr {
+					if err == nil {
+						t.Fatal("migrateNormalizeLegacyParameterKeys() = nil error, want one")
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("migrateNormalizeLegacyParameterKeys() = %v, want no error", err)
+				}
+				if !reflect.DeepEqual(tc.properties, tc.want) {
+					t.Errorf("properties = %+v, want %+v", tc.properties, tc.want)
+				}
+			},
+		)
+	}
+}
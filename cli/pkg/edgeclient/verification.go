@@ -0,0 +1,320 @@
+/*
+Copyright 2024. Open Data Hub Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edgeclient
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"regexp"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	tektonclientset "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+)
+
+// signatureAnnotation is the annotation Tekton's trusted resources feature uses to attach a detached signature to
+// a resolved Pipeline or Task, matching the convention used by tektoncd/chains.
+const signatureAnnotation = "chains.tekton.dev/signature"
+
+// VerificationResult describes the outcome of checking a resolved pipeline (and the tasks it references) against
+// the configured trust policies.
+type VerificationResult struct {
+	// Verified is false if any policy in "enforce" mode failed to verify the pipeline.
+	Verified        bool
+	MatchedPolicies []string
+	Failures        []VerificationFailure
+}
+
+// VerificationFailure records why a single VerificationPolicy failed to verify the pipeline.
+type VerificationFailure struct {
+	Policy string
+	Reason string
+}
+
+// SetTrustPolicy configures the VerificationPolicy objects used to verify the resolved aiedge-e2e pipeline (and
+// the tasks it references) before CreatePipelineRun submits a PipelineRun.
+//
+// Pass policies to use a fixed, already-loaded set of VerificationPolicy objects. Pass a namespace instead (with
+// policies nil) to load the VerificationPolicy CRs from that namespace on every build, using the kubeConfig given
+// to CreatePipelineRun/BuildModelImage at call time. If neither is provided, verification is skipped, matching the
+// current opt-in behavior.
+func (c *Client) SetTrustPolicy(policies []tektonv1alpha1.VerificationPolicy, namespace string) error {
+	if len(policies) == 0 && namespace == "" {
+		return fmt.Errorf("either policies or a namespace to load them from is required")
+	}
+	c.trustPolicies = policies
+	c.trustPolicyNamespace = namespace
+	return nil
+}
+
+// verifyPipelineRef validates the pipeline referenced by pipelineRef, and the tasks it references, against the
+// configured trust policies. It returns a VerificationResult with Verified set to false if any policy in
+// "enforce" mode failed; policies in "warn" mode are logged but never cause Verified to be false. If no trust
+// policies are configured, verification is skipped and the result is always Verified.
+func (c *Client) verifyPipelineRef(
+	ctx context.Context, tektonClient tektonclientset.Interface, namespace, kubeConfig string, pipelineRef *tektonv1.PipelineRef,
+) (*VerificationResult, error) {
+	policies, err := c.loadTrustPolicies(ctx, namespace, kubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trust policies: %w", err)
+	}
+	if len(policies) == 0 {
+		return &VerificationResult{Verified: true}, nil
+	}
+
+	resources, resolveErr := resolvePipelineAndTasks(ctx, tektonClient, namespace, pipelineRef)
+
+	result := &VerificationResult{Verified: true}
+	for _, policy := range policies {
+		matched, reason := verifyAgainstPolicy(policy, resources, resolveErr)
+		if matched {
+			result.MatchedPolicies = append(result.MatchedPolicies, policy.GetName())
+			continue
+		}
+		result.Failures = append(result.Failures, VerificationFailure{Policy: policy.GetName(), Reason: reason})
+		if policy.Spec.Mode == tektonv1alpha1.ModeEnforce {
+			result.Verified = false
+		} else {
+			log.Printf("ai-edge: verification policy %q (mode=warn) failed: %s", policy.GetName(), reason)
+		}
+	}
+	return result, nil
+}
+
+// loadTrustPolicies returns the VerificationPolicy objects to verify against: the fixed set configured via
+// SetTrustPolicy, or those loaded live from c.trustPolicyNamespace when no fixed set was given.
+func (c *Client) loadTrustPolicies(ctx context.Context, namespace, kubeConfig string) ([]tektonv1alpha1.VerificationPolicy, error) {
+	if len(c.trustPolicies) > 0 {
+		return c.trustPolicies, nil
+	}
+	if c.trustPolicyNamespace == "" {
+		return nil, nil
+	}
+
+	config, err := clientcmd.BuildConfigFromFlags("", kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	tektonClient, err := tektonclientset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	policyNamespace := c.trustPolicyNamespace
+	if policyNamespace == "" {
+		policyNamespace = namespace
+	}
+	list, err := tektonClient.TektonV1alpha1().VerificationPolicies(policyNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// resolvedResource is a pipeline or task object resolved for verification, along with the detached signature
+// attached to it (if any) and the name used to match it against a VerificationPolicy's resource patterns.
+type resolvedResource struct {
+	name         string
+	payload      []byte
+	signature    string
+	unverifiable bool
+}
+
+// resolvePipelineAndTasks fetches the Pipeline object named by pipelineRef, and every Task it references, so they
+// can be verified. A Pipeline or Task referenced via a Tekton Resolver (rather than by name) cannot be fetched
+// directly by this client, so it cannot be verified; it is represented as a resolvedResource with unverifiable
+// set, which verifyAgainstPolicy fails closed against every enforce-mode policy rather than only the policies
+// whose resource patterns happen to match its resolver-derived name (that name is attacker-influenced, so a
+// pattern match can't be relied on to catch it). resolvePipelineAndTasks only returns an error for a genuine
+// fetch failure (a named Pipeline/Task that could not be retrieved), not for resolver-based references.
+func resolvePipelineAndTasks(
+	ctx context.Context, tektonClient tektonclientset.Interface, namespace string, pipelineRef *tektonv1.PipelineRef,
+) ([]resolvedResource, error) {
+	if pipelineRef == nil {
+		return nil, fmt.Errorf("no pipeline reference to verify")
+	}
+	if pipelineRef.Name == "" {
+		return []resolvedResource{unverifiableResolverResource("pipeline", pipelineRef.ResolverRef)}, nil
+	}
+
+	pipeline, err := tektonClient.TektonV1().Pipelines(namespace).Get(ctx, pipelineRef.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve pipeline %s for verification: %w", pipelineRef.Name, err)
+	}
+	resources := []resolvedResource{newResolvedResource("pipeline/" + pipeline.GetName())}
+	if err := resources[0].marshal(pipeline.Spec, pipeline.GetAnnotations()); err != nil {
+		return nil, err
+	}
+
+	for _, t := range append(append([]tektonv1.PipelineTask{}, pipeline.Spec.Tasks...), pipeline.Spec.Finally...) {
+		if t.TaskRef == nil {
+			continue
+		}
+		if t.TaskRef.Name == "" {
+			resources = append(resources, unverifiableResolverResource("task", t.TaskRef.ResolverRef))
+			continue
+		}
+		task, err := tektonClient.TektonV1().Tasks(namespace).Get(ctx, t.TaskRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve task %s for verification: %w", t.TaskRef.Name, err)
+		}
+		resource := newResolvedResource("task/" + task.GetName())
+		if err := resource.marshal(task.Spec, task.GetAnnotations()); err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+func newResolvedResource(name string) resolvedResource {
+	return resolvedResource{name: name}
+}
+
+// unverifiableResolverResource represents a Pipeline or Task referenced via a Tekton Resolver (kind is "pipeline"
+// or "task"). Its name is derived from the resolver type and, when present, the "url" or "bundle" param it was
+// given, purely for logging/diagnostics; because that name is chosen by whoever supplied the PipelineSource, it
+// is not trusted as a basis for deciding whether a policy applies (see unverifiable on resolvedResource).
+func unverifiableResolverResource(kind string, resolverRef tektonv1.ResolverRef) resolvedResource {
+	name := fmt.Sprintf("%s/resolver:%s", kind, resolverRef.Resolver)
+	for _, p := range resolverRef.Params {
+		if p.Name == "url" || p.Name == "bundle" {
+			name = fmt.Sprintf("%s:%s", name, p.Value.StringVal)
+			break
+		}
+	}
+	return resolvedResource{name: name, unverifiable: true}
+}
+
+// marshal computes the payload that was (or should have been) signed at signing time: the resource's Spec only,
+// not the live object, since the live object carries server-populated fields (resourceVersion, uid,
+// managedFields, status) that won't match whatever was hashed at signing time, and its own annotations map
+// includes the signatureAnnotation being verified — a signature can never validate a payload that already
+// contains itself. obj should be a Spec (e.g. pipeline.Spec or task.Spec); annotations should be the live
+// object's annotations, which is where the signature itself is read from.
+func (r *resolvedResource) marshal(obj interface{}, annotations map[string]string) error {
+	payload, err := yaml.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s for verification: %w", r.name, err)
+	}
+	r.payload = payload
+	r.signature = annotations[signatureAnnotation]
+	return nil
+}
+
+// verifyAgainstPolicy checks every resolved resource matching one of policy's resource patterns against every
+// authority in the policy, returning true once every matching resource is verified by at least one authority.
+//
+// An unverifiable resource (one resolved via a Tekton Resolver rather than fetched by name, see
+// unverifiableResolverResource) fails every enforce-mode policy unconditionally, regardless of whether its
+// resolver-derived name happens to match that policy's resource patterns: those patterns are written against
+// known, trusted resource names, and a resolver reference lets the caller of CreatePipelineRun choose that name,
+// so matching by pattern here would let an attacker-supplied PipelineSource opt itself out of every policy an
+// admin configured. A warn-mode policy still only warns about resources its patterns match, consistent with
+// warn mode never affecting VerificationResult.Verified.
+func verifyAgainstPolicy(policy tektonv1alpha1.VerificationPolicy, resources []resolvedResource, resolveErr error) (bool, string) {
+	if resolveErr != nil {
+		return false, resolveErr.Error()
+	}
+
+	for _, resource := range resources {
+		if resource.unverifiable && policy.Spec.Mode == tektonv1alpha1.ModeEnforce {
+			return false, fmt.Sprintf(
+				"resource %s was resolved via a Tekton Resolver and cannot be verified against enforce policy %s",
+				resource.name, policy.GetName(),
+			)
+		}
+		if !resourceMatchesPolicy(policy, resource.name) {
+			continue
+		}
+		if resource.signature == "" {
+			return false, fmt.Sprintf("resource %s has no %s annotation to verify", resource.name, signatureAnnotation)
+		}
+
+		verifiedByAnyAuthority := false
+		for _, authority := range policy.Spec.Authorities {
+			if authority.Key == nil || authority.Key.Data == "" {
+				continue
+			}
+			if err := verifyX509Signature(authority.Key.Data, resource.payload, resource.signature); err == nil {
+				verifiedByAnyAuthority = true
+				break
+			}
+		}
+		if !verifiedByAnyAuthority {
+			return false, fmt.Sprintf("no authority in policy %s verified resource %s", policy.GetName(), resource.name)
+		}
+	}
+	return true, ""
+}
+
+// resourceMatchesPolicy reports whether resourceName matches any of policy's resource patterns, each of which is a
+// regular expression as used by Tekton's own VerificationPolicy resources.
+func resourceMatchesPolicy(policy tektonv1alpha1.VerificationPolicy, resourceName string) bool {
+	for _, pattern := range policy.Spec.Resources {
+		matched, err := regexp.MatchString(pattern.Pattern, resourceName)
+		if err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyX509Signature checks signatureB64 against payload using the PEM-encoded public key in pemKey, in the
+// style of cosign's keyed (non-keyless) verification: an ECDSA or RSA signature over the SHA-256 digest of the
+// payload.
+func verifyX509Signature(pemKey string, payload []byte, signatureB64 string) error {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return fmt.Errorf("invalid PEM public key")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse public key: %w", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(signatureB64)
+	if err != nil {
+		return fmt.Errorf("failed to decode signature: %w", err)
+	}
+	digest := sha256.Sum256(payload)
+
+	switch key := pub.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], signature) {
+			return fmt.Errorf("ecdsa signature verification failed")
+		}
+		return nil
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return fmt.Errorf("rsa signature verification failed: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", pub)
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2024. Open Data Hub Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edgeclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// generateTestKeyPair returns a fresh ECDSA key and its PEM-encoded public key, in the form a VerificationPolicy
+// authority's Key.Data would hold.
+func generateTestKeyPair(t *testing.T) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to marshal public key: %v", err)
+	}
+	pemPub := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	return key, string(pemPub)
+}
+
+// signTestPayload signs payload the way verifyX509Signature expects to verify it: an ECDSA signature over the
+// SHA-256 digest, base64-encoded as the signatureAnnotation value would be.
+func signTestPayload(t *testing.T, key *ecdsa.PrivateKey, payload []byte) string {
+	t.Helper()
+	digest := sha256.Sum256(payload)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("failed to sign payload: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(signature)
+}
+
+// TestVerifyX509Signature is a round-trip sign/verify test: it signs a real payload with a real key and checks
+// that verifyX509Signature accepts the signature over that same payload, and rejects it over any other.
+func TestVerifyX509Signature(t *testing.T) {
+	key, pemPub := generateTestKeyPair(t)
+	payload := []byte("pipeline-spec-bytes")
+	signature := signTestPayload(t, key, payload)
+
+	if err := verifyX509Signature(pemPub, payload, signature); err != nil {
+		t.Errorf("verifyX509Signature() = %v, want nil for a signature over the same payload", err)
+	}
+	if err := verifyX509Signature(pemPub, []byte("a-different-payload"), signature); err == nil {
+		t.Error("verifyX509Signature() = nil, want an error for a signature over a different payload")
+	}
+	if err := verifyX509Signature(pemPub, payload, "not-valid-base64!!"); err == nil {
+		t.Error("verifyX509Signature() = nil, want an error for an undecodable signature")
+	}
+}
+
+func TestVerifyAgainstPolicy(t *testing.T) {
+	key, pemPub := generateTestKeyPair(t)
+	payload := []byte("pipeline-spec-bytes")
+	signature := signTestPayload(t, key, payload)
+
+	policy := tektonv1alpha1.VerificationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "trust-aiedge-e2e"},
+		Spec: tektonv1alpha1.VerificationPolicySpec{
+			Mode:      tektonv1alpha1.ModeEnforce,
+			Resources: []tektonv1alpha1.ResourcePattern{{Pattern: "^pipeline/"}},
+			Authorities: []tektonv1alpha1.Authority{
+				{Name: "key1", Key: &tektonv1alpha1.KeyRef{Data: pemPub}},
+			},
+		},
+	}
+
+	cases := []struct {
+		name      string
+		resources []resolvedResource
+		want      bool
+	}{
+		{
+			name: "matching resource verifies with a correct signature",
+			resources: []resolvedResource{
+				{name: "pipeline/aiedge-e2e", payload: payload, signature: signature},
+			},
+			want: true,
+		},
+		{
+			name: "matching resource fails when the payload was tampered with",
+			resources: []resolvedResource{
+				{name: "pipeline/aiedge-e2e", payload: []byte("tampered"), signature: signature},
+			},
+			want: false,
+		},
+		{
+			name: "matching resource fails with no signature at all",
+			resources: []resolvedResource{
+				{name: "pipeline/aiedge-e2e", payload: payload},
+			},
+			want: false,
+		},
+		{
+			name: "non-matching resource does not affect the policy",
+			resources: []resolvedResource{
+				{name: "task/build", payload: []byte("unrelated")},
+			},
+			want: true,
+		},
+		{
+			name: "unverifiable resolver resource fails an enforce policy even when its name doesn't match",
+			resources: []resolvedResource{
+				{name: "task/resolver:git:https://attacker.example/repo.git", unverifiable: true},
+			},
+			want: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(
+			tc.name, func(t *testing.T) {
+				matched, reason := verifyAgainstPolicy(policy, tc.resources, nil)
+				if matched != tc.want {
+					t.Errorf("verifyAgainstPolicy() = (%v, %q), want matched=%v", matched, reason, tc.want)
+				}
+			},
+		)
+	}
+}
+
+func TestVerifyAgainstPolicy_UnverifiableResourceOnlyWarnsUnderWarnMode(t *testing.T) {
+	warnPolicy := tektonv1alpha1.VerificationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "warn-aiedge-e2e"},
+		Spec: tektonv1alpha1.VerificationPolicySpec{
+			Mode:      tektonv1alpha1.ModeWarn,
+			Resources: []tektonv1alpha1.ResourcePattern{{Pattern: "^pipeline/aiedge-e2e$"}},
+		},
+	}
+	resources := []resolvedResource{
+		{name: "pipeline/resolver:git:https://attacker.example/repo.git", unverifiable: true},
+	}
+
+	matched, reason := verifyAgainstPolicy(warnPolicy, resources, nil)
+	if !matched {
+		t.Errorf(
+			"verifyAgainstPolicy() = (false, %q), want matched=true: a warn policy whose pattern doesn't match "+
+				"the resolver resource's name should not be affected by it",
+			reason,
+		)
+	}
+}
+
+func TestVerifyAgainstPolicy_ResolveErrorFailsTheResource(t *testing.T) {
+	policy := tektonv1alpha1.VerificationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "trust-aiedge-e2e"},
+		Spec:       tektonv1alpha1.VerificationPolicySpec{Mode: tektonv1alpha1.ModeEnforce},
+	}
+
+	matched, reason := verifyAgainstPolicy(policy, nil, fmt.Errorf("failed to resolve pipeline for verification"))
+	if matched {
+		t.Errorf("verifyAgainstPolicy() = (true, %q), want false when resolution failed", reason)
+	}
+}